@@ -0,0 +1,365 @@
+// Package canonical defines the canonical Application model: a Cloud Foundry application
+// normalized into the shape consumed by downstream MTA/Kubernetes manifest generation, regardless
+// of whether it was discovered from a static manifest or a live Cloud Controller API.
+package canonical
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Application represents an interpretation of a runtime Cloud Foundry application. This structure differs in that
+// the information it contains has been processed to simplify its transformation to a Kubernetes manifest using MTA
+type Application struct {
+	// Metadata captures the name, labels and annotations in the application.
+	Metadata Metadata `json:",inline"`
+	// Env captures the `env` field values in the CF application manifest.
+	Env map[string]string `json:"env,omitempty"`
+	// Routes represent the routes that are made available by the application.
+	Routes Routes `json:"routes,omitempty"`
+	// Services captures the `services` field values in the CF application manifest.
+	Services Services `json:"services,omitempty"`
+	// Processes captures the `processes` field values in the CF application manifest.
+	Processes Processes `json:"processes,omitempty"`
+	// Sidecars captures the `sidecars` field values in the CF application manifest.
+	Sidecars Sidecars `json:"sidecars,omitempty"`
+	// Stack represents the `stack` field in the application manifest. The value is captured for information
+	// purposes because it has no relevance in Kubernetes.
+	Stack string `json:"stack,omitempty"`
+	// StartupTimeout captures the maximum elapsed time in which an application that is starting is considered to have failed to respond to checks.
+	// An application has to respond to a readiness or health check before the timeout time elapses or else the platform will
+	// fail the deployment of the application. By default its 60 seconds.
+	// https://github.com/cloudfoundry/docs-dev-guide/blob/96f19d9d67f52ac7418c147d5ddaa79c957eec34/deploy-apps/large-app-deploy.html.md.erb#L35
+	StartupTimeout uint `json:"startupTimeout,omitempty"`
+	// Replicas configures the number of Cloud Foundry application instances.
+	Replicas uint `json:"replicas"`
+}
+
+// Metadata captures the name, labels and annotations in the application
+type Metadata struct {
+	// Name capture the `name` field int CF application manifest
+	Name string `json:"name"`
+	// Org captures the organization where the CF application is deployed at runtime. The field is
+	// empty if the application is discovered directly from the CF manifest.
+	Org string `json:"org,omitempty"`
+	// Space captures the `space` where the CF application is deployed at runtime. The field is empty if the
+	// application is discovered directly from the CF manifest. It is equivalent to a Namespace in Kubernetes.
+	Space string `json:"space,omitempty"`
+	// Labels capture the labels as defined in the `labels` field in the CF application manifest
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations capture the annotations as defined in the `annotations` field in the CF application manifest
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Routes represents a slice of Routes
+type Routes []Route
+
+// Route captures the key elements that define a Route in a string that maps to a URL structure. These values
+// are captured as runtime routes, meaning that if the CF Application manifest is configured to disable all routes
+// with the `no-route` value, it will translate into an empty slice.
+// By default CloudFoundry will always attempt to create a route for each application, unless specified by the field `no-route` when true
+// For further details check: https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#no-route
+// and https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#random-route
+// Example
+// ---
+//
+//	...
+//	routes:
+//	- route: example.com
+//	  protocol: http2
+//	- route: www.example.com/foo
+//	- route: tcp-example.com:1234
+type Route struct {
+	// URL captures the Fully Qualified Domain Name of the hostname field in the route. If the hostname contained a port
+	// it is kept in the URL, e.g. `tcp-example.com:1234`.
+	URL string `json:"url"`
+	// Protocol captures the protocol type: http, http2 or tcp. Note that the CF `protocol` field is only available
+	// for CF deployments that use HTTP/2 routing.
+	Protocol RouteProtocol `json:"protocol"`
+}
+
+type RouteProtocol string
+
+const (
+	HTTP  RouteProtocol = "http"
+	HTTPS RouteProtocol = "https"
+	HTTP2 RouteProtocol = "http2"
+	TCP   RouteProtocol = "tcp"
+	GRPC  RouteProtocol = "grpc"
+)
+
+// Services represents a slice of Service
+type Services []Service
+
+// Service contains the specification for an existing Cloud Foundry service required by the application.
+// Examples:
+// ---
+//
+//	...
+//	services:
+//	  - service-1
+//	  - name: service-2
+//	  - name: service-3
+//	    parameters:
+//	      key-1: value-1
+//	      key-2: [value-2, value-3]
+//	      key-3: ... any other kind of value ...
+//	  - name: service-4
+//	    binding_name: binding-1
+type Service struct {
+	// Name represents the name of the Cloud Foundry service required by the application. This field
+	// represents the runtime name of the service, captured from the 3 different cases where
+	// the service name can be listed.
+	// For more information check https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#services-block
+	Name string `json:"name"`
+	// Parameters contain the k/v relationship for the aplication to bind to the service
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// Processes represents a slice of Processes.
+type Processes []Process
+
+// Process represents the abstraction of the specification of a Cloud Foundry Process.
+// For more information check https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#processes
+type Process struct {
+	// Type captures the `type` field in the Process specification. Accepted values are `web` or `worker`
+	Type ProcessType `json:"type,omitempty"`
+	// Lifecycle captures how the process is staged: from a buildpack, or from a prebuilt container
+	// image. At most one of Lifecycle.Buildpack or Lifecycle.Docker may be set.
+	Lifecycle Lifecycle `json:"lifecycle,omitempty"`
+	// Command represents the command used to run the process.
+	Command []string `json:"command,omitempty"`
+	// DiskQuota represents the amount of persistent disk requested by the process, converted via
+	// units.CFToSI so it is directly parseable as a Kubernetes resource.Quantity.
+	DiskQuota string `json:"disk,omitempty"`
+	// DiskQuotaRaw preserves the original CF manifest string (e.g. "1G") that DiskQuota was derived
+	// from, for round-tripping back to a CF manifest.
+	DiskQuotaRaw string `json:"diskRaw,omitempty"`
+	// Memory represents the amount of memory requested by the process, converted via units.CFToSI
+	// so it is directly parseable as a Kubernetes resource.Quantity.
+	Memory string `json:"memory,omitempty"`
+	// MemoryRaw preserves the original CF manifest string (e.g. "512M") that Memory was derived
+	// from, for round-tripping back to a CF manifest.
+	MemoryRaw string `json:"memoryRaw,omitempty"`
+	// HealthCheck captures the health check information
+	HealthCheck Probe `json:"healthCheck"`
+	// ReadinessCheck captures the readiness check information.
+	ReadinessCheck Probe `json:"readinessCheck"`
+	// Replicas represents the number of instances for this process to run.
+	Replicas uint `json:"replicas"`
+	// LogRateLimit represents the maximum amount of logs to be captured per second, converted via
+	// units.CFToSI so it is directly parseable as a Kubernetes resource.Quantity.
+	LogRateLimit string `json:"logRateLimit,omitempty"`
+	// LogRateLimitRaw preserves the original CF manifest string (e.g. "1KB") that LogRateLimit was
+	// derived from, for round-tripping back to a CF manifest.
+	LogRateLimitRaw string `json:"logRateLimitRaw,omitempty"`
+	// Ports captures the container ports this process listens on, each labeled with its L7
+	// protocol so K8s Service/Istio generation can classify traffic. A Route's protocol describes
+	// how CF routes to the app; Ports describes what the container itself exposes, including ports
+	// with no route at all.
+	Ports []AppPort `json:"ports,omitempty"`
+}
+
+// AppPort represents a single container port exposed by a Process, labeled with the L7 protocol a
+// service mesh should use to classify traffic on it.
+type AppPort struct {
+	// Port is the container port number.
+	Port int32 `json:"port"`
+	// Protocol is the L7 protocol spoken on Port: http, http2, tcp or grpc.
+	Protocol RouteProtocol `json:"protocol"`
+	// Name identifies the port, e.g. for use as a Kubernetes Service/ContainerPort name. Must be a
+	// valid DNS-1123 label and unique within the owning Process's Ports.
+	Name string `json:"name,omitempty"`
+}
+
+// defaultWebPort is the port CF assumes a `web` process listens on when the manifest does not
+// specify one explicitly.
+const defaultWebPort = 8080
+
+// DerivePorts returns the AppPorts for a process of the given type, given the raw `ports` manifest
+// attribute (nil/empty when absent). When ports are explicit, they are returned as-is. Otherwise a
+// `web` process defaults to a single HTTP port 8080, and any other process type has no implicit
+// port, matching CF's own default routing behaviour.
+func DerivePorts(raw []int32, processType ProcessType) []AppPort {
+	if len(raw) > 0 {
+		ports := make([]AppPort, 0, len(raw))
+		for _, p := range raw {
+			ports = append(ports, AppPort{Port: p, Protocol: HTTP})
+		}
+		return ports
+	}
+	if processType == Web {
+		return []AppPort{{Port: defaultWebPort, Protocol: HTTP}}
+	}
+	return nil
+}
+
+// dns1123LabelPattern matches a valid Kubernetes DNS-1123 label.
+var dns1123LabelPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidatePorts checks that every named port in ports is a valid DNS-1123 label and that names are
+// unique within the slice. Unnamed ports are not checked for uniqueness against each other.
+func ValidatePorts(ports []AppPort) error {
+	seen := map[string]bool{}
+	for _, p := range ports {
+		if p.Name == "" {
+			continue
+		}
+		if len(p.Name) > 63 || !dns1123LabelPattern.MatchString(p.Name) {
+			return fmt.Errorf("port name %q is not a valid DNS-1123 label", p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate port name %q", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
+
+// Validate rejects a Lifecycle that specifies both a Buildpack and a Docker variant, since CF apps
+// stage from exactly one of the two.
+func (l Lifecycle) Validate() error {
+	if l.Buildpack != nil && l.Docker != nil {
+		return fmt.Errorf("process lifecycle cannot specify both buildpack and docker")
+	}
+	return nil
+}
+
+// Lifecycle is a discriminated union capturing how a CF process is staged: from one or more
+// buildpacks, or from a prebuilt container image. For more information check
+// https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#buildpacks
+// and https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#docker
+type Lifecycle struct {
+	// Buildpack captures the `buildpacks`/`stack` attributes. Nil when the process stages from a
+	// Docker image instead.
+	Buildpack *BuildpackLifecycle `json:"buildpack,omitempty"`
+	// Docker captures the `docker` attribute. Nil when the process stages from buildpacks instead.
+	Docker *DockerLifecycle `json:"docker,omitempty"`
+}
+
+// BuildpackLifecycle represents a process staged from one or more CF buildpacks.
+type BuildpackLifecycle struct {
+	// Names represents the `buildpacks` field, e.g. ["ruby_buildpack", "java_buildpack"].
+	Names []string `json:"names,omitempty"`
+	// Stack represents the `stack` field for this process. When empty, the process falls back to
+	// Application.Stack.
+	Stack string `json:"stack,omitempty"`
+}
+
+// DockerLifecycle represents a process staged from a prebuilt container image, mirroring the CF
+// manifest's `docker.image`/`docker.username` attributes.
+type DockerLifecycle struct {
+	// Image represents the pull spec of the container image.
+	Image string `json:"image"`
+	// Username represents the `docker.username` field, used alongside PasswordRef to authenticate
+	// against a private registry.
+	Username string `json:"username,omitempty"`
+	// PasswordRef references where the registry password is sourced from, rather than carrying the
+	// credential inline.
+	PasswordRef SecretRef `json:"passwordRef,omitempty"`
+}
+
+// SecretRef references a named credential source, either an environment variable or an external
+// secret path, rather than embedding the credential value directly.
+type SecretRef struct {
+	// EnvVar names an environment variable in the deployment environment holding the credential.
+	EnvVar string `json:"envVar,omitempty"`
+	// SecretPath references an external secret store path (e.g. a Vault path or a Kubernetes
+	// Secret name/key) holding the credential.
+	SecretPath string `json:"secretPath,omitempty"`
+}
+
+// EffectiveStack returns the Buildpack stack for this Lifecycle, falling back to appStack (typically
+// Application.Stack) when the process did not override it. It returns an empty string for a Docker
+// lifecycle, where stack selection does not apply.
+func (l Lifecycle) EffectiveStack(appStack string) string {
+	if l.Buildpack == nil {
+		return ""
+	}
+	if l.Buildpack.Stack != "" {
+		return l.Buildpack.Stack
+	}
+	return appStack
+}
+
+type Sidecars []Sidecar
+
+// Sidecar captures the information of a Sidecar process
+// https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#sidecars
+type Sidecar struct {
+	// Name represents the name of the Sidecar
+	Name string `json:"name"`
+	// ProcessTypes captures the different process types defined for the sidecar.
+	// Compared to a Process, which has only one type, sidecar processes can accumulate more than one type.
+	ProcessTypes ProcessTypes `json:"processTypes"`
+	// Command captures the command to use to run the sidecar
+	Command []string `json:"command"`
+	// Memory represents the amount of memory to allocate to the sidecar, converted via units.CFToSI
+	// so it is directly parseable as a Kubernetes resource.Quantity. It's an optional field.
+	Memory string `json:"memory,omitempty"`
+	// MemoryRaw preserves the original CF manifest string (e.g. "256M") that Memory was derived
+	// from, for round-tripping back to a CF manifest.
+	MemoryRaw string `json:"memoryRaw,omitempty"`
+}
+
+// Probe captures the fields for managing health checks, covering both the `health-check-*` and
+// `readiness-health-check-*` manifest attribute families. For more information check
+// https://docs.cloudfoundry.org/devguide/deploy-apps/healthchecks.html
+type Probe struct {
+	// Type represents the `health-check-type`/`readiness-health-check-type` field: http, port,
+	// process or none.
+	Type ProbeType `json:"type,omitempty"`
+	// Endpoint represents the URL location where to perform the probe check. Only valid when
+	// Type is HTTP.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Port represents the TCP port to probe, for a Port probe. Validate rejects it only on a
+	// Process probe.
+	Port uint `json:"port,omitempty"`
+	// Timeout represents the number of seconds in which the probe check can be considered as timedout.
+	// https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#timeout
+	Timeout uint `json:"timeout"`
+	// InvocationTimeout represents the `health-check-invocation-timeout`/
+	// `readiness-health-check-invocation-timeout` field: the number of seconds allowed for a single
+	// probe invocation to complete, as opposed to Timeout which bounds the overall startup window.
+	InvocationTimeout uint `json:"invocationTimeout,omitempty"`
+	// Interval represents the number of seconds between probe checks.
+	Interval uint `json:"interval"`
+}
+
+// Validate enforces the constraints CF places on combinations of Type, Endpoint and Port: Endpoint
+// is only meaningful for HTTP probes, and Process probes forbid both Endpoint and Port since they
+// check the container PID directly.
+func (p Probe) Validate() error {
+	if p.Endpoint != "" && p.Type != ProbeHTTP {
+		return fmt.Errorf("probe endpoint is only valid when type is %q, got %q", ProbeHTTP, p.Type)
+	}
+	if p.Type == ProbeProcess && (p.Endpoint != "" || p.Port != 0) {
+		return fmt.Errorf("probe type %q forbids endpoint and port", ProbeProcess)
+	}
+	return nil
+}
+
+// ProbeType represents the CF health-check-type/readiness-health-check-type taxonomy.
+type ProbeType string
+
+const (
+	// ProbeHTTP checks an HTTP endpoint for a 200 response.
+	ProbeHTTP ProbeType = "http"
+	// ProbePort checks that the process is listening on Port.
+	ProbePort ProbeType = "port"
+	// ProbeProcess checks that the process is still running, with no network check.
+	ProbeProcess ProbeType = "process"
+	// ProbeNone disables health checking entirely.
+	ProbeNone ProbeType = "none"
+)
+
+type ProcessTypes []ProcessType
+
+type ProcessType string
+
+const (
+	// Web represents a `web` application type
+	Web ProcessType = "web"
+	// Worker represents a `worker` application type
+	Worker ProcessType = "worker"
+)
@@ -0,0 +1,336 @@
+package canonical
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jordigilh/cf-discovery-canonical-form/units"
+)
+
+// DecodeManifest parses a CF application manifest (after manifest.ApplySubstitution has resolved
+// any `((variable))` placeholders) and canonicalizes every application it describes.
+func DecodeManifest(src []byte) ([]Application, error) {
+	var raw rawManifest
+	if err := yaml.Unmarshal(src, &raw); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+
+	apps := make([]Application, 0, len(raw.Applications))
+	for _, ra := range raw.Applications {
+		app, err := decodeApplication(ra)
+		if err != nil {
+			return nil, fmt.Errorf("decoding application %q: %w", ra.Name, err)
+		}
+		apps = append(apps, app)
+	}
+	return apps, nil
+}
+
+// rawManifest mirrors the top-level shape of a CF application manifest.
+type rawManifest struct {
+	Applications []rawApplication `yaml:"applications"`
+}
+
+// rawApplication mirrors the per-application attributes of a CF manifest. Memory/DiskQuota/
+// LogRateLimit/Instances double as the defaults for a single implicit `web` process when
+// Processes is empty, matching CF's own manifest semantics.
+type rawApplication struct {
+	Name         string            `yaml:"name"`
+	Env          map[string]string `yaml:"env"`
+	Routes       []rawRoute        `yaml:"routes"`
+	Services     []rawService      `yaml:"services"`
+	Stack        string            `yaml:"stack"`
+	Instances    uint              `yaml:"instances"`
+	Memory       string            `yaml:"memory"`
+	DiskQuota    string            `yaml:"disk_quota"`
+	LogRateLimit string            `yaml:"log-rate-limit-per-second"`
+	Processes    []rawProcess      `yaml:"processes"`
+	Sidecars     []rawSidecar      `yaml:"sidecars"`
+	Buildpacks   []string          `yaml:"buildpacks"`
+	Docker       *rawDocker        `yaml:"docker"`
+	Ports        []int32           `yaml:"ports"`
+}
+
+// rawProcess mirrors the per-process attributes of a CF manifest `processes` entry.
+type rawProcess struct {
+	Type         string `yaml:"type"`
+	Command      string `yaml:"command"`
+	Memory       string `yaml:"memory"`
+	DiskQuota    string `yaml:"disk_quota"`
+	LogRateLimit string `yaml:"log-rate-limit-per-second"`
+	Instances    uint   `yaml:"instances"`
+
+	HealthCheckType              string `yaml:"health-check-type"`
+	HealthCheckHTTPEndpoint      string `yaml:"health-check-http-endpoint"`
+	HealthCheckInvocationTimeout uint   `yaml:"health-check-invocation-timeout"`
+	Timeout                      uint   `yaml:"timeout"`
+
+	ReadinessHealthCheckType              string `yaml:"readiness-health-check-type"`
+	ReadinessHealthCheckHTTPEndpoint      string `yaml:"readiness-health-check-http-endpoint"`
+	ReadinessHealthCheckInvocationTimeout uint   `yaml:"readiness-health-check-invocation-timeout"`
+	ReadinessHealthCheckInterval          uint   `yaml:"readiness-health-check-interval"`
+
+	Buildpacks []string   `yaml:"buildpacks"`
+	Stack      string     `yaml:"stack"`
+	Docker     *rawDocker `yaml:"docker"`
+
+	Ports []int32 `yaml:"ports"`
+}
+
+// rawDocker mirrors the `docker` manifest block.
+type rawDocker struct {
+	Image    string `yaml:"image"`
+	Username string `yaml:"username"`
+}
+
+// rawRoute mirrors a single `routes` entry.
+type rawRoute struct {
+	Route    string `yaml:"route"`
+	Protocol string `yaml:"protocol"`
+}
+
+// rawService mirrors a single `services` entry, which CF manifests allow to be either a bare
+// service name string or a map with `name` and optional `parameters`/`binding_name`.
+type rawService struct {
+	Name       string                 `yaml:"name"`
+	Parameters map[string]interface{} `yaml:"parameters"`
+}
+
+// UnmarshalYAML implements the polymorphic `services` entry shape: a plain scalar is taken as the
+// service name, otherwise the node is decoded as a map.
+func (s *rawService) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Name = node.Value
+		return nil
+	}
+	type plain rawService
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*s = rawService(p)
+	return nil
+}
+
+// rawSidecar mirrors a single `sidecars` entry.
+type rawSidecar struct {
+	Name         string   `yaml:"name"`
+	ProcessTypes []string `yaml:"process_types"`
+	Command      string   `yaml:"command"`
+	Memory       string   `yaml:"memory"`
+}
+
+// defaultProcess synthesizes the implicit `web` process CF assumes when a manifest sets
+// memory/disk/instances at the application level rather than under `processes`.
+func defaultProcess(ra rawApplication) rawProcess {
+	return rawProcess{
+		Type:         string(Web),
+		Memory:       ra.Memory,
+		DiskQuota:    ra.DiskQuota,
+		LogRateLimit: ra.LogRateLimit,
+		Instances:    ra.Instances,
+		Ports:        ra.Ports,
+	}
+}
+
+// withAppLifecycleDefaults falls a rawProcess's Buildpacks/Docker back to the app-level values when
+// the process itself sets neither, and its Stack back to the app-level stack when unset, mirroring how CF lets a `processes:` entry override
+// staging per-process but otherwise inherit it from the top-level manifest attributes:
+// https://docs.cloudfoundry.org/devguide/deploy-apps/manifest-attributes.html#buildpacks
+func withAppLifecycleDefaults(rp rawProcess, ra rawApplication) rawProcess {
+	if len(rp.Buildpacks) == 0 && rp.Docker == nil {
+		rp.Buildpacks = ra.Buildpacks
+		rp.Docker = ra.Docker
+	}
+	if rp.Stack == "" {
+		rp.Stack = ra.Stack
+	}
+	return rp
+}
+
+func decodeApplication(ra rawApplication) (Application, error) {
+	rawProcs := ra.Processes
+	if len(rawProcs) == 0 {
+		rawProcs = []rawProcess{defaultProcess(ra)}
+	}
+
+	procs := make(Processes, 0, len(rawProcs))
+	for _, rp := range rawProcs {
+		rp = withAppLifecycleDefaults(rp, ra)
+		p, err := decodeProcess(rp)
+		if err != nil {
+			return Application{}, fmt.Errorf("process %q: %w", rp.Type, err)
+		}
+		procs = append(procs, p)
+	}
+
+	routes := make(Routes, 0, len(ra.Routes))
+	for _, rr := range ra.Routes {
+		routes = append(routes, Route{URL: rr.Route, Protocol: RouteProtocol(rr.Protocol)})
+	}
+
+	services := make(Services, 0, len(ra.Services))
+	for _, rs := range ra.Services {
+		services = append(services, Service{Name: rs.Name, Parameters: rs.Parameters})
+	}
+
+	sidecars := make(Sidecars, 0, len(ra.Sidecars))
+	for _, rsc := range ra.Sidecars {
+		memory, memoryRaw, err := convertSize(rsc.Memory)
+		if err != nil {
+			return Application{}, fmt.Errorf("sidecar %q: %w", rsc.Name, err)
+		}
+		types := make(ProcessTypes, 0, len(rsc.ProcessTypes))
+		for _, t := range rsc.ProcessTypes {
+			types = append(types, ProcessType(t))
+		}
+		sidecars = append(sidecars, Sidecar{
+			Name:         rsc.Name,
+			ProcessTypes: types,
+			Command:      CommandSlice(rsc.Command),
+			Memory:       memory,
+			MemoryRaw:    memoryRaw,
+		})
+	}
+
+	return Application{
+		Metadata:  Metadata{Name: ra.Name},
+		Env:       ra.Env,
+		Routes:    routes,
+		Services:  services,
+		Processes: procs,
+		Sidecars:  sidecars,
+		Stack:     ra.Stack,
+		Replicas:  ra.Instances,
+	}, nil
+}
+
+func decodeProcess(rp rawProcess) (Process, error) {
+	memory, memoryRaw, err := convertSize(rp.Memory)
+	if err != nil {
+		return Process{}, err
+	}
+	disk, diskRaw, err := convertSize(rp.DiskQuota)
+	if err != nil {
+		return Process{}, err
+	}
+	logRate, logRateRaw, err := convertSize(rp.LogRateLimit)
+	if err != nil {
+		return Process{}, err
+	}
+
+	healthCheck := Probe{
+		Type:              ProbeType(rp.HealthCheckType),
+		Endpoint:          rp.HealthCheckHTTPEndpoint,
+		InvocationTimeout: rp.HealthCheckInvocationTimeout,
+		Timeout:           rp.Timeout,
+	}
+	if healthCheck.Type == "" {
+		// CF defaults health-check-type to `port` when the manifest does not set it.
+		healthCheck.Type = ProbePort
+	}
+	if err := healthCheck.Validate(); err != nil {
+		return Process{}, fmt.Errorf("health check: %w", err)
+	}
+
+	readinessCheck := Probe{
+		Type:              ProbeType(rp.ReadinessHealthCheckType),
+		Endpoint:          rp.ReadinessHealthCheckHTTPEndpoint,
+		InvocationTimeout: rp.ReadinessHealthCheckInvocationTimeout,
+		Interval:          rp.ReadinessHealthCheckInterval,
+	}
+	if readinessCheck.Type == "" {
+		// CF defaults readiness-health-check-type to the process's effective health-check-type.
+		readinessCheck.Type = healthCheck.Type
+	}
+	if readinessCheck.Endpoint == "" {
+		// CF defaults readiness-health-check-http-endpoint to health-check-http-endpoint unless
+		// the manifest overrides it explicitly.
+		readinessCheck.Endpoint = rp.HealthCheckHTTPEndpoint
+	}
+	if readinessCheck.InvocationTimeout == 0 {
+		// Same defaulting rule applies to readiness-health-check-invocation-timeout.
+		readinessCheck.InvocationTimeout = rp.HealthCheckInvocationTimeout
+	}
+	if err := readinessCheck.Validate(); err != nil {
+		return Process{}, fmt.Errorf("readiness health check: %w", err)
+	}
+
+	lifecycle := decodeLifecycle(rp)
+	if err := lifecycle.Validate(); err != nil {
+		return Process{}, fmt.Errorf("lifecycle: %w", err)
+	}
+
+	processType := ProcessType(rp.Type)
+	ports := DerivePorts(rp.Ports, processType)
+	if err := ValidatePorts(ports); err != nil {
+		return Process{}, fmt.Errorf("ports: %w", err)
+	}
+
+	return Process{
+		Type:            processType,
+		Lifecycle:       lifecycle,
+		Command:         CommandSlice(rp.Command),
+		Memory:          memory,
+		MemoryRaw:       memoryRaw,
+		DiskQuota:       disk,
+		DiskQuotaRaw:    diskRaw,
+		LogRateLimit:    logRate,
+		LogRateLimitRaw: logRateRaw,
+		HealthCheck:     healthCheck,
+		ReadinessCheck:  readinessCheck,
+		Replicas:        rp.Instances,
+		Ports:           ports,
+	}, nil
+}
+
+// dockerPasswordEnvVar is the environment variable CF CLI reads a private Docker registry password
+// from; manifests never carry the password inline.
+const dockerPasswordEnvVar = "CF_DOCKER_PASSWORD"
+
+// decodeLifecycle builds the Buildpack and/or Docker variant of Lifecycle from rp's raw attributes.
+// It does not itself enforce mutual exclusion: when rp sets both buildpacks and docker, both
+// variants are set, and callers must call Lifecycle.Validate to reject that.
+func decodeLifecycle(rp rawProcess) Lifecycle {
+	var lifecycle Lifecycle
+	if rp.Docker != nil {
+		lifecycle.Docker = &DockerLifecycle{
+			Image:    rp.Docker.Image,
+			Username: rp.Docker.Username,
+		}
+		if rp.Docker.Username != "" {
+			lifecycle.Docker.PasswordRef = SecretRef{EnvVar: dockerPasswordEnvVar}
+		}
+	}
+	if len(rp.Buildpacks) > 0 || (rp.Docker == nil && rp.Stack != "") {
+		lifecycle.Buildpack = &BuildpackLifecycle{
+			Names: rp.Buildpacks,
+			Stack: rp.Stack,
+		}
+	}
+	return lifecycle
+}
+
+// convertSize converts a CF manifest size string to its K8s-safe equivalent via units.CFToSI,
+// returning both the converted and original values, or ("", "", nil) when raw is empty.
+func convertSize(raw string) (converted, original string, err error) {
+	if raw == "" {
+		return "", "", nil
+	}
+	si, err := units.CFToSI(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("converting size %q: %w", raw, err)
+	}
+	return si, raw, nil
+}
+
+// CommandSlice wraps a single command string as the []string Command shape Process and Sidecar
+// expect, or nil when command is empty. Both the manifest decoder and the CC discovery client use
+// it, since CF only ever carries a single shell command string in either source.
+func CommandSlice(command string) []string {
+	if command == "" {
+		return nil
+	}
+	return []string{command}
+}
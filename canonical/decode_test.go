@@ -0,0 +1,200 @@
+package canonical
+
+import "testing"
+
+func TestDecodeManifest_FullApplication(t *testing.T) {
+	src := []byte(`
+applications:
+- name: my-app
+  stack: cflinuxfs4
+  instances: 2
+  env:
+    FOO: bar
+  routes:
+  - route: my-app.example.com
+    protocol: http2
+  services:
+  - my-db
+  processes:
+  - type: web
+    command: bundle exec rackup
+    memory: 512M
+    disk_quota: 1G
+    instances: 3
+    buildpacks: [ruby_buildpack]
+    health-check-type: http
+    health-check-http-endpoint: /health
+    health-check-invocation-timeout: 5
+    readiness-health-check-type: http
+    ports: [8080]
+  - type: worker
+    command: bundle exec rake jobs:work
+    memory: 256M
+    health-check-type: http
+    health-check-http-endpoint: /worker-health
+    health-check-invocation-timeout: 9
+`)
+
+	apps, err := DecodeManifest(src)
+	if err != nil {
+		t.Fatalf("DecodeManifest: %v", err)
+	}
+	if len(apps) != 1 {
+		t.Fatalf("got %d applications, want 1", len(apps))
+	}
+	app := apps[0]
+
+	if app.Metadata.Name != "my-app" || app.Stack != "cflinuxfs4" || app.Replicas != 2 {
+		t.Fatalf("Application = %#v, want name/stack/replicas my-app/cflinuxfs4/2", app)
+	}
+	if app.Env["FOO"] != "bar" {
+		t.Fatalf("Env = %#v, want FOO=bar", app.Env)
+	}
+	if len(app.Routes) != 1 || app.Routes[0].URL != "my-app.example.com" || app.Routes[0].Protocol != HTTP2 {
+		t.Fatalf("Routes = %#v, want a single http2 my-app.example.com route", app.Routes)
+	}
+	if len(app.Services) != 1 || app.Services[0].Name != "my-db" {
+		t.Fatalf("Services = %#v, want a single my-db service", app.Services)
+	}
+	if len(app.Processes) != 2 {
+		t.Fatalf("got %d processes, want 2", len(app.Processes))
+	}
+
+	web := app.Processes[0]
+	if web.Type != Web || web.Replicas != 3 {
+		t.Fatalf("web process = %#v, want type web with 3 replicas", web)
+	}
+	if web.Memory != "512Mi" || web.DiskQuota != "1Gi" {
+		t.Fatalf("web process sizes = memory=%q disk=%q, want 512Mi/1Gi", web.Memory, web.DiskQuota)
+	}
+	if len(web.Command) != 1 || web.Command[0] != "bundle exec rackup" {
+		t.Fatalf("web Command = %#v, want a single-element slice", web.Command)
+	}
+	if web.Lifecycle.Buildpack == nil || len(web.Lifecycle.Buildpack.Names) != 1 || web.Lifecycle.Buildpack.Names[0] != "ruby_buildpack" {
+		t.Fatalf("web Lifecycle = %#v, want a single ruby_buildpack buildpack", web.Lifecycle)
+	}
+	if web.HealthCheck.Type != ProbeHTTP || web.HealthCheck.Endpoint != "/health" {
+		t.Fatalf("web HealthCheck = %#v, want http probe on /health", web.HealthCheck)
+	}
+	if web.ReadinessCheck.Type != ProbeHTTP || web.ReadinessCheck.Endpoint != "/health" || web.ReadinessCheck.InvocationTimeout != 5 {
+		t.Fatalf("web ReadinessCheck = %#v, want it to inherit endpoint/timeout from the health check", web.ReadinessCheck)
+	}
+	if len(web.Ports) != 1 || web.Ports[0].Port != 8080 {
+		t.Fatalf("web Ports = %#v, want explicit port 8080", web.Ports)
+	}
+
+	worker := app.Processes[1]
+	if worker.Type != ProcessType("worker") || worker.Replicas != 0 {
+		t.Fatalf("worker process = %#v, want type worker with default replicas", worker)
+	}
+	if worker.HealthCheck.Type != ProbeHTTP || worker.HealthCheck.Endpoint != "/worker-health" {
+		t.Fatalf("worker HealthCheck = %#v, want http probe on /worker-health", worker.HealthCheck)
+	}
+	if worker.ReadinessCheck.Type != ProbeHTTP {
+		t.Fatalf("worker ReadinessCheck.Type = %q, want it to default to the process's effective health-check type", worker.ReadinessCheck.Type)
+	}
+	if worker.ReadinessCheck.Endpoint != "/worker-health" {
+		t.Fatalf("worker ReadinessCheck.Endpoint = %q, want it to inherit the health check's /worker-health endpoint", worker.ReadinessCheck.Endpoint)
+	}
+	if worker.ReadinessCheck.InvocationTimeout != 9 {
+		t.Fatalf("worker ReadinessCheck.InvocationTimeout = %d, want it to inherit the health check's 9", worker.ReadinessCheck.InvocationTimeout)
+	}
+	if len(worker.Ports) != 0 {
+		t.Fatalf("worker Ports = %#v, want none for a non-web process with no explicit ports", worker.Ports)
+	}
+}
+
+func TestDecodeManifest_DockerLifecycleAndDefaultProcess(t *testing.T) {
+	src := []byte(`
+applications:
+- name: docker-app
+  memory: 1G
+  instances: 1
+  docker:
+    image: registry.example.com/my-app:latest
+    username: deployer
+`)
+
+	apps, err := DecodeManifest(src)
+	if err != nil {
+		t.Fatalf("DecodeManifest: %v", err)
+	}
+	if len(apps) != 1 || len(apps[0].Processes) != 1 {
+		t.Fatalf("apps = %#v, want one application with its implicit web process", apps)
+	}
+
+	web := apps[0].Processes[0]
+	if web.Type != Web {
+		t.Fatalf("Type = %q, want the implicit web process", web.Type)
+	}
+	if web.Lifecycle.Docker == nil || web.Lifecycle.Docker.Image != "registry.example.com/my-app:latest" {
+		t.Fatalf("Lifecycle.Docker = %#v, want the manifest's image", web.Lifecycle.Docker)
+	}
+	if web.Lifecycle.Docker.PasswordRef.EnvVar != dockerPasswordEnvVar {
+		t.Fatalf("PasswordRef = %#v, want EnvVar %q", web.Lifecycle.Docker.PasswordRef, dockerPasswordEnvVar)
+	}
+	if web.Memory != "1Gi" {
+		t.Fatalf("Memory = %q, want 1Gi (from the app-level default process)", web.Memory)
+	}
+}
+
+func TestDecodeManifest_ExplicitProcessesInheritAppLifecycle(t *testing.T) {
+	src := []byte(`
+applications:
+- name: my-app
+  stack: cflinuxfs4
+  buildpacks: [ruby_buildpack]
+  processes:
+  - type: web
+    memory: 512M
+  - type: worker
+    memory: 256M
+    buildpacks: [go_buildpack]
+    stack: cflinuxfs3
+`)
+
+	apps, err := DecodeManifest(src)
+	if err != nil {
+		t.Fatalf("DecodeManifest: %v", err)
+	}
+	if len(apps) != 1 || len(apps[0].Processes) != 2 {
+		t.Fatalf("apps = %#v, want one application with two explicit processes", apps)
+	}
+
+	web := apps[0].Processes[0]
+	if web.Lifecycle.Buildpack == nil || len(web.Lifecycle.Buildpack.Names) != 1 || web.Lifecycle.Buildpack.Names[0] != "ruby_buildpack" {
+		t.Fatalf("web Lifecycle = %#v, want it to inherit the app-level ruby_buildpack", web.Lifecycle)
+	}
+	if web.Lifecycle.Buildpack.Stack != "cflinuxfs4" {
+		t.Fatalf("web Lifecycle.Buildpack.Stack = %q, want it to inherit the app-level cflinuxfs4", web.Lifecycle.Buildpack.Stack)
+	}
+
+	worker := apps[0].Processes[1]
+	if worker.Lifecycle.Buildpack == nil || len(worker.Lifecycle.Buildpack.Names) != 1 || worker.Lifecycle.Buildpack.Names[0] != "go_buildpack" {
+		t.Fatalf("worker Lifecycle = %#v, want its own explicit go_buildpack override", worker.Lifecycle)
+	}
+	if worker.Lifecycle.Buildpack.Stack != "cflinuxfs3" {
+		t.Fatalf("worker Lifecycle.Buildpack.Stack = %q, want its own explicit override", worker.Lifecycle.Buildpack.Stack)
+	}
+}
+
+func TestDecodeManifest_ExplicitProcessInheritsAppLevelDocker(t *testing.T) {
+	src := []byte(`
+applications:
+- name: my-app
+  docker:
+    image: registry.example.com/my-app:latest
+  processes:
+  - type: web
+    memory: 512M
+`)
+
+	apps, err := DecodeManifest(src)
+	if err != nil {
+		t.Fatalf("DecodeManifest: %v", err)
+	}
+	web := apps[0].Processes[0]
+	if web.Lifecycle.Docker == nil || web.Lifecycle.Docker.Image != "registry.example.com/my-app:latest" {
+		t.Fatalf("web Lifecycle.Docker = %#v, want it to inherit the app-level image", web.Lifecycle.Docker)
+	}
+}
@@ -0,0 +1,52 @@
+package canonical
+
+import "testing"
+
+func TestLifecycleValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		lifecycle Lifecycle
+		wantErr   bool
+	}{
+		{"buildpack only", Lifecycle{Buildpack: &BuildpackLifecycle{Names: []string{"ruby_buildpack"}}}, false},
+		{"docker only", Lifecycle{Docker: &DockerLifecycle{Image: "repo/app:latest"}}, false},
+		{"neither", Lifecycle{}, false},
+		{"both buildpack and docker", Lifecycle{
+			Buildpack: &BuildpackLifecycle{Names: []string{"ruby_buildpack"}},
+			Docker:    &DockerLifecycle{Image: "repo/app:latest"},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.lifecycle.Validate()
+			if tt.wantErr && err == nil {
+				t.Fatalf("Validate() = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestLifecycleEffectiveStack(t *testing.T) {
+	tests := []struct {
+		name      string
+		lifecycle Lifecycle
+		appStack  string
+		want      string
+	}{
+		{"buildpack overrides app stack", Lifecycle{Buildpack: &BuildpackLifecycle{Stack: "cflinuxfs4"}}, "cflinuxfs3", "cflinuxfs4"},
+		{"buildpack falls back to app stack", Lifecycle{Buildpack: &BuildpackLifecycle{}}, "cflinuxfs3", "cflinuxfs3"},
+		{"docker has no stack", Lifecycle{Docker: &DockerLifecycle{Image: "repo/app:latest"}}, "cflinuxfs3", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.lifecycle.EffectiveStack(tt.appStack); got != tt.want {
+				t.Fatalf("EffectiveStack(%q) = %q, want %q", tt.appStack, got, tt.want)
+			}
+		})
+	}
+}
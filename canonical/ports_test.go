@@ -0,0 +1,62 @@
+package canonical
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDerivePorts(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         []int32
+		processType ProcessType
+		want        []AppPort
+	}{
+		{"explicit ports returned as-is", []int32{8080, 9090}, Web, []AppPort{{Port: 8080, Protocol: HTTP}, {Port: 9090, Protocol: HTTP}}},
+		{"web defaults to 8080", nil, Web, []AppPort{{Port: defaultWebPort, Protocol: HTTP}}},
+		{"worker has no implicit port", nil, Worker, nil},
+		{"explicit ports override default for web", []int32{1234}, Web, []AppPort{{Port: 1234, Protocol: HTTP}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DerivePorts(tt.raw, tt.processType)
+			if len(got) != len(tt.want) {
+				t.Fatalf("DerivePorts() = %#v, want %#v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("DerivePorts()[%d] = %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePorts(t *testing.T) {
+	tests := []struct {
+		name    string
+		ports   []AppPort
+		wantErr bool
+	}{
+		{"unnamed ports", []AppPort{{Port: 8080}, {Port: 9090}}, false},
+		{"valid unique names", []AppPort{{Port: 8080, Name: "web"}, {Port: 9090, Name: "metrics"}}, false},
+		{"empty names not checked for uniqueness", []AppPort{{Port: 8080}, {Port: 9090}}, false},
+		{"duplicate names", []AppPort{{Port: 8080, Name: "web"}, {Port: 9090, Name: "web"}}, true},
+		{"uppercase not a valid DNS-1123 label", []AppPort{{Port: 8080, Name: "Web"}}, true},
+		{"name with underscore not valid", []AppPort{{Port: 8080, Name: "web_1"}}, true},
+		{"name too long", []AppPort{{Port: 8080, Name: strings.Repeat("a", 64)}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePorts(tt.ports)
+			if tt.wantErr && err == nil {
+				t.Fatalf("ValidatePorts() = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("ValidatePorts() = %v, want nil", err)
+			}
+		})
+	}
+}
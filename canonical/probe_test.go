@@ -0,0 +1,33 @@
+package canonical
+
+import "testing"
+
+func TestProbeValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		probe   Probe
+		wantErr bool
+	}{
+		{"http with endpoint", Probe{Type: ProbeHTTP, Endpoint: "/healthz"}, false},
+		{"http without endpoint", Probe{Type: ProbeHTTP}, false},
+		{"port with port", Probe{Type: ProbePort, Port: 8080}, false},
+		{"process with neither", Probe{Type: ProbeProcess}, false},
+		{"none", Probe{Type: ProbeNone}, false},
+		{"endpoint on non-http type", Probe{Type: ProbePort, Endpoint: "/healthz"}, true},
+		{"endpoint on process type", Probe{Type: ProbeProcess, Endpoint: "/healthz"}, true},
+		{"port on process type", Probe{Type: ProbeProcess, Port: 8080}, true},
+		{"endpoint and port on process type", Probe{Type: ProbeProcess, Endpoint: "/healthz", Port: 8080}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.probe.Validate()
+			if tt.wantErr && err == nil {
+				t.Fatalf("Validate() = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
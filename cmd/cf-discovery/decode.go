@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jordigilh/cf-discovery-canonical-form/canonical"
+	"github.com/jordigilh/cf-discovery-canonical-form/manifest"
+)
+
+// runDecode implements `cf-discovery decode`: it canonicalizes a static CF application manifest,
+// resolving `((variable))` placeholders first, and prints the result as JSON to stdout.
+func runDecode(args []string) error {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	manifestPath := fs.String("manifest", "", "path to the CF application manifest (required)")
+	var varsFiles stringSliceFlag
+	fs.Var(&varsFiles, "vars-file", "path to a --vars-file YAML document; may be repeated")
+	var varFlags stringSliceFlag
+	fs.Var(&varFlags, "var", "a key=value variable substitution; may be repeated and overrides --vars-file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *manifestPath == "" {
+		return fmt.Errorf("--manifest is required")
+	}
+
+	src, err := os.ReadFile(*manifestPath)
+	if err != nil {
+		return fmt.Errorf("reading manifest %q: %w", *manifestPath, err)
+	}
+
+	var vars []map[string]interface{}
+	if len(varFlags) > 0 {
+		cliVars := map[string]interface{}{}
+		for _, v := range varFlags {
+			name, value, err := manifest.ParseVarFlag(v)
+			if err != nil {
+				return err
+			}
+			cliVars[name] = value
+		}
+		vars = append(vars, cliVars)
+	}
+	for _, path := range varsFiles {
+		fileVars, err := manifest.LoadVarsFile(path)
+		if err != nil {
+			return err
+		}
+		vars = append(vars, fileVars)
+	}
+
+	resolved, err := manifest.ApplySubstitution(src, vars...)
+	if err != nil {
+		return fmt.Errorf("resolving manifest variables: %w", err)
+	}
+
+	apps, err := canonical.DecodeManifest(resolved)
+	if err != nil {
+		return fmt.Errorf("decoding manifest %q: %w", *manifestPath, err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(apps)
+}
+
+// stringSliceFlag collects repeated occurrences of a flag, e.g. `--var a=1 --var b=2`.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return fmt.Sprint([]string(*s))
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
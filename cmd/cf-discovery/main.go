@@ -0,0 +1,36 @@
+// Command cf-discovery canonicalizes Cloud Foundry applications, either from a static manifest or
+// from a live Cloud Controller API.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "serve":
+		err = runServe(os.Args[2:])
+	case "decode":
+		err = runDecode(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cf-discovery:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: cf-discovery serve [flags]")
+	fmt.Fprintln(os.Stderr, "       cf-discovery decode [flags]")
+}
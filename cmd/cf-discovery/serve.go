@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jordigilh/cf-discovery-canonical-form/discovery/cc"
+	"github.com/jordigilh/cf-discovery-canonical-form/server"
+	"github.com/jordigilh/cf-discovery-canonical-form/server/store"
+)
+
+// runServe implements `cf-discovery serve`: it periodically polls a live Cloud Controller API and
+// exposes the canonicalized, cached results over HTTP.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	apiRoot := fs.String("api", "", "Cloud Controller API root, e.g. https://api.example.com (required)")
+	uaaTokenURL := fs.String("uaa-token-url", "", "UAA token endpoint, e.g. https://uaa.example.com/oauth/token (required)")
+	clientID := fs.String("client-id", "", "UAA client_credentials client id")
+	clientSecret := fs.String("client-secret", "", "UAA client_credentials client secret")
+	refreshInterval := fs.Duration("refresh-interval", server.DefaultRefreshInterval, "how often to poll Cloud Controller and how long cached results stay fresh")
+	redisAddr := fs.String("redis-addr", "", "optional Redis address; when unset, an in-memory cache is used")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *apiRoot == "" || *uaaTokenURL == "" {
+		return fmt.Errorf("--api and --uaa-token-url are required")
+	}
+
+	client := cc.NewClientCredentialsClient(*apiRoot, *uaaTokenURL, *clientID, *clientSecret)
+
+	cacheStore, err := newStore(*redisAddr)
+	if err != nil {
+		return err
+	}
+
+	srv := server.New(server.Config{
+		Discoverer:      client,
+		Store:           cacheStore,
+		RefreshInterval: *refreshInterval,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go srv.Start(ctx)
+
+	httpServer := &http.Server{Addr: *addr, Handler: srv.Handler()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("cf-discovery: listening on %s, refreshing every %s", *addr, *refreshInterval)
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("serving: %w", err)
+	}
+	return nil
+}
+
+// newStore constructs the in-memory Store, or a Redis-backed one when redisAddr is set.
+func newStore(redisAddr string) (store.Store, error) {
+	if redisAddr == "" {
+		return store.NewMemory(), nil
+	}
+	return store.NewRedisAddr(redisAddr)
+}
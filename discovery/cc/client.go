@@ -0,0 +1,126 @@
+// Package cc discovers Cloud Foundry applications from a live Cloud Controller v3 API, assembling
+// fully-populated canonical.Application values rather than parsing a static manifest.
+package cc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/jordigilh/cf-discovery-canonical-form/canonical"
+)
+
+// DefaultTimeout bounds a single HTTP round trip to the Cloud Controller API.
+const DefaultTimeout = 30 * time.Second
+
+// Client discovers canonical.Application values from a live CF foundation's Cloud Controller v3
+// API, authenticating against UAA.
+type Client struct {
+	apiRoot    string
+	httpClient *http.Client
+}
+
+// NewClientCredentialsClient constructs a Client authenticated against uaaTokenURL using the
+// `client_credentials` OAuth2 grant, suitable for service-to-service discovery.
+func NewClientCredentialsClient(apiRoot, uaaTokenURL, clientID, clientSecret string) *Client {
+	cfg := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     uaaTokenURL,
+	}
+	return newClient(apiRoot, cfg.TokenSource(context.Background()))
+}
+
+// NewPasswordClient constructs a Client authenticated against uaaTokenURL using the `password`
+// OAuth2 grant, matching how CF CLI users typically authenticate.
+func NewPasswordClient(ctx context.Context, apiRoot, uaaTokenURL, clientID, clientSecret, username, password string) (*Client, error) {
+	cfg := oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: uaaTokenURL},
+	}
+	token, err := cfg.PasswordCredentialsToken(ctx, username, password)
+	if err != nil {
+		return nil, fmt.Errorf("authenticating to UAA as %q: %w", username, err)
+	}
+	return newClient(apiRoot, cfg.TokenSource(ctx, token)), nil
+}
+
+func newClient(apiRoot string, tokenSource oauth2.TokenSource) *Client {
+	return &Client{
+		apiRoot: apiRoot,
+		httpClient: &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: &oauth2.Transport{Source: tokenSource, Base: &rateLimitedRetryTransport{}},
+		},
+	}
+}
+
+// DiscoverAll walks every organization, space, app, process, route and service credential binding
+// visible to the authenticated principal and returns the fully-populated canonical.Application for
+// each app.
+func (c *Client) DiscoverAll(ctx context.Context) ([]canonical.Application, error) {
+	var apps []canonical.Application
+	stream, errs := c.DiscoverStream(ctx)
+	for a := range stream {
+		apps = append(apps, a)
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// DiscoverStream is the streaming variant of DiscoverAll, for large foundations where holding every
+// Application in memory at once is undesirable. The returned Application channel is closed when
+// discovery completes; the caller must then receive from the error channel (which holds at most one
+// value) to learn whether discovery succeeded.
+func (c *Client) DiscoverStream(ctx context.Context) (<-chan canonical.Application, <-chan error) {
+	out := make(chan canonical.Application)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errs)
+
+		orgs, err := c.listOrgs(ctx)
+		if err != nil {
+			errs <- fmt.Errorf("listing organizations: %w", err)
+			return
+		}
+
+		for _, org := range orgs {
+			spaces, err := c.listSpaces(ctx, org.GUID)
+			if err != nil {
+				errs <- fmt.Errorf("listing spaces in org %q: %w", org.Name, err)
+				return
+			}
+			for _, space := range spaces {
+				apps, err := c.listApps(ctx, space.GUID)
+				if err != nil {
+					errs <- fmt.Errorf("listing apps in space %q: %w", space.Name, err)
+					return
+				}
+				for _, app := range apps {
+					canon, err := c.assembleApplication(ctx, org, space, app)
+					if err != nil {
+						errs <- fmt.Errorf("assembling app %q: %w", app.Name, err)
+						return
+					}
+					select {
+					case out <- canon:
+					case <-ctx.Done():
+						errs <- ctx.Err()
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return out, errs
+}
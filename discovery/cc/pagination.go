@@ -0,0 +1,55 @@
+package cc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// pagination mirrors the CC v3 `pagination` response envelope.
+type pagination struct {
+	Next *struct {
+		Href string `json:"href"`
+	} `json:"next"`
+}
+
+// get issues a GET against path (relative to the API root, or an absolute `pagination.next.href`
+// URL) and decodes the JSON body into out.
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	url := path
+	if len(path) > 0 && path[0] == '/' {
+		url = c.apiRoot + path
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("building request for %q: %w", url, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting %q: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("requesting %q: unexpected status %d: %s", url, resp.StatusCode, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response from %q: %w", url, err)
+	}
+	return nil
+}
+
+// nextPath returns the path to fetch for the next page of a CC v3 list response, or "" when there
+// are no more pages.
+func nextPath(p pagination) string {
+	if p.Next == nil {
+		return ""
+	}
+	return p.Next.Href
+}
@@ -0,0 +1,65 @@
+package cc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListOrgs_FollowsPagination(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		switch len(requests) {
+		case 1:
+			fmt.Fprintf(w, `{"pagination":{"next":{"href":%q}},"resources":[{"guid":"org-1","name":"org-one"}]}`, "/v3/organizations?page=2")
+		default:
+			fmt.Fprint(w, `{"pagination":{"next":null},"resources":[{"guid":"org-2","name":"org-two"}]}`)
+		}
+	}))
+	defer srv.Close()
+
+	client := &Client{apiRoot: srv.URL, httpClient: srv.Client()}
+	orgs, err := client.listOrgs(context.Background())
+	if err != nil {
+		t.Fatalf("listOrgs: %v", err)
+	}
+
+	if len(orgs) != 2 {
+		t.Fatalf("got %d orgs, want 2 (across both pages): %#v", len(orgs), orgs)
+	}
+	if orgs[0].Name != "org-one" || orgs[1].Name != "org-two" {
+		t.Fatalf("orgs = %#v, want [org-one org-two] in page order", orgs)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("made %d requests, want 2 (one per page)", len(requests))
+	}
+}
+
+func TestListOrgs_PropagatesErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errors":[{"detail":"not authorized"}]}`)
+	}))
+	defer srv.Close()
+
+	client := &Client{apiRoot: srv.URL, httpClient: srv.Client()}
+	if _, err := client.listOrgs(context.Background()); err == nil {
+		t.Fatalf("listOrgs succeeded, want error for a 403 response")
+	}
+}
+
+func TestNextPath(t *testing.T) {
+	if got := nextPath(pagination{}); got != "" {
+		t.Fatalf("nextPath(no next) = %q, want empty", got)
+	}
+	p := pagination{}
+	p.Next = &struct {
+		Href string `json:"href"`
+	}{Href: "/v3/organizations?page=2"}
+	if got := nextPath(p); got != "/v3/organizations?page=2" {
+		t.Fatalf("nextPath(with next) = %q, want the next href", got)
+	}
+}
@@ -0,0 +1,419 @@
+package cc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jordigilh/cf-discovery-canonical-form/canonical"
+	"github.com/jordigilh/cf-discovery-canonical-form/units"
+)
+
+// org is the subset of a CC v3 organization resource this package cares about.
+type org struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+// space is the subset of a CC v3 space resource this package cares about.
+type space struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+// app is the subset of a CC v3 app resource this package cares about.
+type app struct {
+	GUID      string `json:"guid"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Lifecycle struct {
+		Type string `json:"type"`
+		Data struct {
+			Buildpacks []string `json:"buildpacks"`
+			Stack      string   `json:"stack"`
+		} `json:"data"`
+	} `json:"lifecycle"`
+}
+
+// process is the subset of a CC v3 process resource this package cares about.
+type process struct {
+	GUID        string `json:"guid"`
+	Type        string `json:"type"`
+	Instances   uint   `json:"instances"`
+	MemoryMB    uint   `json:"memory_in_mb"`
+	DiskMB      uint   `json:"disk_in_mb"`
+	Command     string `json:"command"`
+	HealthCheck struct {
+		Type string `json:"type"`
+		Data struct {
+			Timeout           uint   `json:"timeout"`
+			InvocationTimeout uint   `json:"invocation_timeout"`
+			Endpoint          string `json:"endpoint"`
+		} `json:"data"`
+	} `json:"health_check"`
+	ReadinessCheck struct {
+		Type string `json:"type"`
+		Data struct {
+			InvocationTimeout uint   `json:"invocation_timeout"`
+			Endpoint          string `json:"endpoint"`
+		} `json:"data"`
+	} `json:"readiness_health_check"`
+}
+
+// route is the subset of a CC v3 route resource this package cares about. The URL is assembled
+// from Host, Path, Port and the related domain's name, since older Cloud Controller API versions
+// do not return a precomputed `url` field.
+type route struct {
+	Host          string `json:"host"`
+	Path          string `json:"path"`
+	Port          int    `json:"port"`
+	Protocol      string `json:"protocol"`
+	Relationships struct {
+		Domain struct {
+			Data struct {
+				GUID string `json:"guid"`
+			} `json:"data"`
+		} `json:"domain"`
+	} `json:"relationships"`
+}
+
+// serviceCredentialBinding is the subset of a CC v3 service_credential_binding resource this
+// package cares about.
+type serviceCredentialBinding struct {
+	Name   string                 `json:"name"`
+	Params map[string]interface{} `json:"parameters"`
+}
+
+func (c *Client) listOrgs(ctx context.Context) ([]org, error) {
+	var all []org
+	path := "/v3/organizations"
+	for path != "" {
+		var page pagedOrgs
+		if err := c.get(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Resources...)
+		path = nextPath(page.Pagination)
+	}
+	return all, nil
+}
+
+type pagedOrgs struct {
+	Pagination pagination `json:"pagination"`
+	Resources  []org      `json:"resources"`
+}
+
+func (c *Client) listSpaces(ctx context.Context, orgGUID string) ([]space, error) {
+	var all []space
+	path := fmt.Sprintf("/v3/spaces?organization_guids=%s", orgGUID)
+	for path != "" {
+		var page pagedSpaces
+		if err := c.get(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Resources...)
+		path = nextPath(page.Pagination)
+	}
+	return all, nil
+}
+
+type pagedSpaces struct {
+	Pagination pagination `json:"pagination"`
+	Resources  []space    `json:"resources"`
+}
+
+func (c *Client) listApps(ctx context.Context, spaceGUID string) ([]app, error) {
+	var all []app
+	path := fmt.Sprintf("/v3/apps?space_guids=%s", spaceGUID)
+	for path != "" {
+		var page pagedApps
+		if err := c.get(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Resources...)
+		path = nextPath(page.Pagination)
+	}
+	return all, nil
+}
+
+type pagedApps struct {
+	Pagination pagination `json:"pagination"`
+	Resources  []app      `json:"resources"`
+}
+
+func (c *Client) listProcesses(ctx context.Context, appGUID string) ([]process, error) {
+	var all []process
+	path := fmt.Sprintf("/v3/apps/%s/processes", appGUID)
+	for path != "" {
+		var page pagedProcesses
+		if err := c.get(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Resources...)
+		path = nextPath(page.Pagination)
+	}
+	return all, nil
+}
+
+type pagedProcesses struct {
+	Pagination pagination `json:"pagination"`
+	Resources  []process  `json:"resources"`
+}
+
+// processInstances fetches the live replica count for a single process type, from
+// /v3/apps/{guid}/processes/{type}/instances.
+func (c *Client) processInstances(ctx context.Context, appGUID, processType string) (uint, error) {
+	var resp struct {
+		Resources map[string]struct {
+			State string `json:"state"`
+		} `json:"resources"`
+	}
+	path := fmt.Sprintf("/v3/apps/%s/processes/%s/instances", appGUID, processType)
+	if err := c.get(ctx, path, &resp); err != nil {
+		return 0, err
+	}
+	return uint(len(resp.Resources)), nil
+}
+
+func (c *Client) listRoutes(ctx context.Context, appGUID string) ([]route, error) {
+	var all []route
+	path := fmt.Sprintf("/v3/apps/%s/routes", appGUID)
+	for path != "" {
+		var page pagedRoutes
+		if err := c.get(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Resources...)
+		path = nextPath(page.Pagination)
+	}
+	return all, nil
+}
+
+type pagedRoutes struct {
+	Pagination pagination `json:"pagination"`
+	Resources  []route    `json:"resources"`
+}
+
+// domain is the subset of a CC v3 domain resource this package cares about.
+type domain struct {
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+// domainName resolves a single domain GUID to its name. Domains are few per foundation relative to
+// routes, so each lookup is a single uncached request; callers walking many routes for the same app
+// will typically share a handful of domain GUIDs.
+func (c *Client) domainName(ctx context.Context, domainGUID string) (string, error) {
+	var d domain
+	if err := c.get(ctx, fmt.Sprintf("/v3/domains/%s", domainGUID), &d); err != nil {
+		return "", err
+	}
+	return d.Name, nil
+}
+
+// routeURL joins a route's host, domain, path and port into the fully-qualified URL CF would
+// route traffic to, mirroring https://docs.cloudfoundry.org/devguide/deploy-apps/routes-domains.html.
+func routeURL(host, domainName, path string, port int) string {
+	url := domainName
+	if host != "" {
+		url = host + "." + domainName
+	}
+	if port != 0 {
+		url = fmt.Sprintf("%s:%d", url, port)
+	}
+	return url + path
+}
+
+func (c *Client) getEnv(ctx context.Context, appGUID string) (map[string]string, error) {
+	var resp struct {
+		EnvironmentVariables map[string]string `json:"environment_variables"`
+	}
+	path := fmt.Sprintf("/v3/apps/%s/env", appGUID)
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.EnvironmentVariables, nil
+}
+
+func (c *Client) listServiceCredentialBindings(ctx context.Context, appGUID string) ([]serviceCredentialBinding, error) {
+	var all []serviceCredentialBinding
+	path := fmt.Sprintf("/v3/service_credential_bindings?app_guids=%s", appGUID)
+	for path != "" {
+		var page pagedServiceCredentialBindings
+		if err := c.get(ctx, path, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Resources...)
+		path = nextPath(page.Pagination)
+	}
+	return all, nil
+}
+
+type pagedServiceCredentialBindings struct {
+	Pagination pagination                 `json:"pagination"`
+	Resources  []serviceCredentialBinding `json:"resources"`
+}
+
+// assembleApplication joins an app's processes, routes, env and service bindings into a fully
+// populated canonical.Application.
+func (c *Client) assembleApplication(ctx context.Context, o org, s space, a app) (canonical.Application, error) {
+	procs, err := c.listProcesses(ctx, a.GUID)
+	if err != nil {
+		return canonical.Application{}, fmt.Errorf("listing processes: %w", err)
+	}
+
+	routes, err := c.listRoutes(ctx, a.GUID)
+	if err != nil {
+		return canonical.Application{}, fmt.Errorf("listing routes: %w", err)
+	}
+
+	env, err := c.getEnv(ctx, a.GUID)
+	if err != nil {
+		return canonical.Application{}, fmt.Errorf("fetching env: %w", err)
+	}
+
+	bindings, err := c.listServiceCredentialBindings(ctx, a.GUID)
+	if err != nil {
+		return canonical.Application{}, fmt.Errorf("listing service credential bindings: %w", err)
+	}
+
+	lifecycle := appLifecycle(a)
+	if err := lifecycle.Validate(); err != nil {
+		return canonical.Application{}, fmt.Errorf("lifecycle: %w", err)
+	}
+
+	canonProcs := make(canonical.Processes, 0, len(procs))
+	for _, p := range procs {
+		replicas, err := c.processInstances(ctx, a.GUID, p.Type)
+		if err != nil {
+			return canonical.Application{}, fmt.Errorf("fetching instance count for process %q: %w", p.Type, err)
+		}
+
+		memory, memoryRaw, err := convertMB(p.MemoryMB)
+		if err != nil {
+			return canonical.Application{}, fmt.Errorf("process %q memory: %w", p.Type, err)
+		}
+		disk, diskRaw, err := convertMB(p.DiskMB)
+		if err != nil {
+			return canonical.Application{}, fmt.Errorf("process %q disk: %w", p.Type, err)
+		}
+
+		healthCheck := canonical.Probe{
+			Type:              canonical.ProbeType(p.HealthCheck.Type),
+			Endpoint:          p.HealthCheck.Data.Endpoint,
+			Timeout:           p.HealthCheck.Data.Timeout,
+			InvocationTimeout: p.HealthCheck.Data.InvocationTimeout,
+		}
+		if err := healthCheck.Validate(); err != nil {
+			return canonical.Application{}, fmt.Errorf("process %q health check: %w", p.Type, err)
+		}
+
+		readinessCheck := canonical.Probe{
+			Type:              canonical.ProbeType(p.ReadinessCheck.Type),
+			Endpoint:          p.ReadinessCheck.Data.Endpoint,
+			InvocationTimeout: p.ReadinessCheck.Data.InvocationTimeout,
+		}
+		if readinessCheck.Type == "" {
+			// CC defaults the readiness check's type to the process's effective health-check type.
+			readinessCheck.Type = healthCheck.Type
+		}
+		if readinessCheck.Endpoint == "" {
+			readinessCheck.Endpoint = p.HealthCheck.Data.Endpoint
+		}
+		if readinessCheck.InvocationTimeout == 0 {
+			readinessCheck.InvocationTimeout = p.HealthCheck.Data.InvocationTimeout
+		}
+		if err := readinessCheck.Validate(); err != nil {
+			return canonical.Application{}, fmt.Errorf("process %q readiness check: %w", p.Type, err)
+		}
+
+		processType := canonical.ProcessType(p.Type)
+		ports := canonical.DerivePorts(nil, processType)
+		if err := canonical.ValidatePorts(ports); err != nil {
+			return canonical.Application{}, fmt.Errorf("process %q ports: %w", p.Type, err)
+		}
+
+		canonProcs = append(canonProcs, canonical.Process{
+			Type:           processType,
+			Lifecycle:      lifecycle,
+			Command:        canonical.CommandSlice(p.Command),
+			Memory:         memory,
+			MemoryRaw:      memoryRaw,
+			DiskQuota:      disk,
+			DiskQuotaRaw:   diskRaw,
+			HealthCheck:    healthCheck,
+			ReadinessCheck: readinessCheck,
+			Replicas:       replicas,
+			Ports:          ports,
+		})
+	}
+
+	domainNames := map[string]string{}
+	canonRoutes := make(canonical.Routes, 0, len(routes))
+	for _, r := range routes {
+		domainGUID := r.Relationships.Domain.Data.GUID
+		name, ok := domainNames[domainGUID]
+		if !ok {
+			resolved, err := c.domainName(ctx, domainGUID)
+			if err != nil {
+				return canonical.Application{}, fmt.Errorf("resolving domain %q: %w", domainGUID, err)
+			}
+			name = resolved
+			domainNames[domainGUID] = name
+		}
+		canonRoutes = append(canonRoutes, canonical.Route{
+			URL:      routeURL(r.Host, name, r.Path, r.Port),
+			Protocol: canonical.RouteProtocol(r.Protocol),
+		})
+	}
+
+	canonServices := make(canonical.Services, 0, len(bindings))
+	for _, b := range bindings {
+		canonServices = append(canonServices, canonical.Service{
+			Name:       b.Name,
+			Parameters: b.Params,
+		})
+	}
+
+	return canonical.Application{
+		Metadata: canonical.Metadata{
+			Name:  a.Name,
+			Org:   o.Name,
+			Space: s.Name,
+		},
+		Env:       env,
+		Routes:    canonRoutes,
+		Services:  canonServices,
+		Processes: canonProcs,
+		Stack:     lifecycle.EffectiveStack(""),
+	}, nil
+}
+
+// appLifecycle translates a CC v3 app resource's `lifecycle` block into a canonical.Lifecycle.
+// Unlike a CF manifest, CC v3 staging is app-wide rather than per-process, so every process of the
+// app shares the same Lifecycle value. For a docker-lifecycle app, this returns an empty
+// DockerLifecycle since the image pull spec lives on the app's current droplet, not on the app
+// resource itself; callers needing it must resolve the current droplet separately.
+func appLifecycle(a app) canonical.Lifecycle {
+	if a.Lifecycle.Type == "docker" {
+		return canonical.Lifecycle{Docker: &canonical.DockerLifecycle{}}
+	}
+	return canonical.Lifecycle{Buildpack: &canonical.BuildpackLifecycle{
+		Names: a.Lifecycle.Data.Buildpacks,
+		Stack: a.Lifecycle.Data.Stack,
+	}}
+}
+
+// convertMB converts a CC v3 memory_in_mb/disk_in_mb value (already MiB-equivalent) into its
+// Kubernetes resource.Quantity form via units.CFToSI, alongside the CF-notation string it was
+// derived from. Returns ("", "", nil) for a zero value, matching an unset manifest attribute.
+func convertMB(mb uint) (converted, raw string, err error) {
+	if mb == 0 {
+		return "", "", nil
+	}
+	raw = fmt.Sprintf("%dM", mb)
+	si, err := units.CFToSI(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("converting %q: %w", raw, err)
+	}
+	return si, raw, nil
+}
@@ -0,0 +1,128 @@
+package cc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// ccFixture wires up a minimal CC v3 API covering every endpoint assembleApplication touches:
+// processes, instances, routes, domains, env and service credential bindings.
+func ccFixture(t *testing.T, processJSON string) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/apps/app-1/processes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"pagination":{"next":null},"resources":[%s]}`, processJSON)
+	})
+	mux.HandleFunc("/v3/apps/app-1/processes/web/instances", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"resources":{"0":{"state":"RUNNING"},"1":{"state":"RUNNING"}}}`)
+	})
+	mux.HandleFunc("/v3/apps/app-1/routes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"pagination":{"next":null},"resources":[{"host":"myapp","path":"","protocol":"http1","relationships":{"domain":{"data":{"guid":"domain-1"}}}}]}`)
+	})
+	mux.HandleFunc("/v3/domains/domain-1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"guid":"domain-1","name":"apps.example.com"}`)
+	})
+	mux.HandleFunc("/v3/apps/app-1/env", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"environment_variables":{"FOO":"bar"}}`)
+	})
+	mux.HandleFunc("/v3/service_credential_bindings", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"pagination":{"next":null},"resources":[{"name":"my-db","parameters":null}]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return &Client{apiRoot: srv.URL, httpClient: srv.Client()}
+}
+
+func TestAssembleApplication(t *testing.T) {
+	processJSON := `{
+		"guid":"proc-1",
+		"type":"web",
+		"instances":2,
+		"memory_in_mb":512,
+		"disk_in_mb":1024,
+		"command":"bundle exec rackup",
+		"health_check":{"type":"http","data":{"timeout":10,"invocation_timeout":5,"endpoint":"/health"}},
+		"readiness_health_check":{"type":"http","data":{"invocation_timeout":3,"endpoint":"/ready"}}
+	}`
+	client := ccFixture(t, processJSON)
+
+	o := org{GUID: "org-1", Name: "my-org"}
+	s := space{GUID: "space-1", Name: "my-space"}
+	a := app{GUID: "app-1", Name: "my-app"}
+	a.Lifecycle.Type = "buildpack"
+	a.Lifecycle.Data.Buildpacks = []string{"ruby_buildpack"}
+	a.Lifecycle.Data.Stack = "cflinuxfs4"
+
+	result, err := client.assembleApplication(context.Background(), o, s, a)
+	if err != nil {
+		t.Fatalf("assembleApplication: %v", err)
+	}
+
+	if result.Metadata.Name != "my-app" || result.Metadata.Org != "my-org" || result.Metadata.Space != "my-space" {
+		t.Fatalf("Metadata = %#v, want my-app/my-org/my-space", result.Metadata)
+	}
+	if result.Env["FOO"] != "bar" {
+		t.Fatalf("Env = %#v, want FOO=bar", result.Env)
+	}
+	if len(result.Routes) != 1 || result.Routes[0].URL != "myapp.apps.example.com" {
+		t.Fatalf("Routes = %#v, want a single myapp.apps.example.com route", result.Routes)
+	}
+	if len(result.Services) != 1 || result.Services[0].Name != "my-db" {
+		t.Fatalf("Services = %#v, want a single my-db binding", result.Services)
+	}
+
+	if len(result.Processes) != 1 {
+		t.Fatalf("Processes = %#v, want exactly one", result.Processes)
+	}
+	p := result.Processes[0]
+	if p.Replicas != 2 {
+		t.Fatalf("Replicas = %d, want 2 (from the live instances endpoint)", p.Replicas)
+	}
+	if string(p.HealthCheck.Type) != "http" || p.HealthCheck.Endpoint != "/health" {
+		t.Fatalf("HealthCheck = %#v, want http probe on /health", p.HealthCheck)
+	}
+	if string(p.ReadinessCheck.Type) != "http" || p.ReadinessCheck.Endpoint != "/ready" {
+		t.Fatalf("ReadinessCheck = %#v, want http probe on /ready", p.ReadinessCheck)
+	}
+	if p.Lifecycle.Buildpack == nil || p.Lifecycle.Buildpack.Stack != "cflinuxfs4" {
+		t.Fatalf("Lifecycle = %#v, want a buildpack lifecycle on cflinuxfs4", p.Lifecycle)
+	}
+	if len(p.Command) != 1 || p.Command[0] != "bundle exec rackup" {
+		t.Fatalf("Command = %#v, want a single-element slice", p.Command)
+	}
+}
+
+func TestAssembleApplication_ReadinessCheckDefaultsFromHealthCheck(t *testing.T) {
+	processJSON := `{
+		"guid":"proc-1",
+		"type":"web",
+		"instances":1,
+		"command":"rackup",
+		"health_check":{"type":"http","data":{"endpoint":"/health","invocation_timeout":7}}
+	}`
+	client := ccFixture(t, processJSON)
+
+	o := org{GUID: "org-1", Name: "my-org"}
+	s := space{GUID: "space-1", Name: "my-space"}
+	a := app{GUID: "app-1", Name: "my-app"}
+	a.Lifecycle.Type = "buildpack"
+
+	result, err := client.assembleApplication(context.Background(), o, s, a)
+	if err != nil {
+		t.Fatalf("assembleApplication: %v", err)
+	}
+
+	p := result.Processes[0]
+	if string(p.ReadinessCheck.Type) != "http" {
+		t.Fatalf("ReadinessCheck.Type = %q, want it to default to the health check's http type", p.ReadinessCheck.Type)
+	}
+	if p.ReadinessCheck.Endpoint != "/health" {
+		t.Fatalf("ReadinessCheck.Endpoint = %q, want it to default to the health check's /health", p.ReadinessCheck.Endpoint)
+	}
+	if p.ReadinessCheck.InvocationTimeout != 7 {
+		t.Fatalf("ReadinessCheck.InvocationTimeout = %d, want it to default to the health check's 7", p.ReadinessCheck.InvocationTimeout)
+	}
+}
@@ -0,0 +1,83 @@
+package cc
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetries bounds how many times a request is retried after a 429 or 5xx response.
+const maxRetries = 5
+
+// baseBackoff is the delay before the first retry; subsequent retries double it.
+const baseBackoff = 500 * time.Millisecond
+
+// rateLimitedRetryTransport wraps an http.RoundTripper, retrying 429 and 5xx responses with
+// exponential backoff and pausing when the Cloud Controller reports, via an
+// `X-RateLimit-Remaining: 0` header, that the client has no requests left.
+type rateLimitedRetryTransport struct {
+	Base http.RoundTripper
+}
+
+func (t *rateLimitedRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var bodyBytes []byte
+	if req.Body != nil {
+		bodyBytes, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		}
+
+		resp, err = base.RoundTrip(req)
+		if err != nil {
+			if attempt < maxRetries {
+				time.Sleep(backoffDelay(attempt))
+			}
+			continue
+		}
+
+		if remaining, ok := rateLimitRemaining(resp); ok && remaining == 0 {
+			time.Sleep(backoffDelay(attempt))
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return resp, nil
+		}
+
+		if attempt < maxRetries {
+			resp.Body.Close()
+			time.Sleep(backoffDelay(attempt))
+			continue
+		}
+	}
+	return resp, err
+}
+
+func rateLimitRemaining(resp *http.Response) (int, bool) {
+	header := resp.Header.Get("X-RateLimit-Remaining")
+	if header == "" {
+		return 0, false
+	}
+	remaining, err := strconv.Atoi(header)
+	if err != nil {
+		return 0, false
+	}
+	return remaining, true
+}
+
+func backoffDelay(attempt int) time.Duration {
+	return time.Duration(math.Pow(2, float64(attempt))) * baseBackoff
+}
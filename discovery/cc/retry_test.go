@@ -0,0 +1,93 @@
+package cc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay_Doubles(t *testing.T) {
+	want := []time.Duration{
+		baseBackoff,
+		2 * baseBackoff,
+		4 * baseBackoff,
+	}
+	for attempt, w := range want {
+		if got := backoffDelay(attempt); got != w {
+			t.Fatalf("backoffDelay(%d) = %v, want %v", attempt, got, w)
+		}
+	}
+}
+
+func TestRateLimitRemaining(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		wantOK    bool
+		wantValue int
+	}{
+		{"absent header", "", false, 0},
+		{"valid value", "42", true, 42},
+		{"zero value", "0", true, 0},
+		{"non-numeric value", "not-a-number", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("X-RateLimit-Remaining", tt.header)
+			}
+			value, ok := rateLimitRemaining(resp)
+			if ok != tt.wantOK || value != tt.wantValue {
+				t.Fatalf("rateLimitRemaining() = (%d, %v), want (%d, %v)", value, ok, tt.wantValue, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRateLimitedRetryTransport_RetriesOn5xxThenSucceeds(t *testing.T) {
+	var requestCount int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &rateLimitedRetryTransport{}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d after retry", resp.StatusCode, http.StatusOK)
+	}
+	if requestCount != 2 {
+		t.Fatalf("server saw %d requests, want exactly 2 (initial + one retry)", requestCount)
+	}
+}
+
+func TestRateLimitedRetryTransport_PassesThroughClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &rateLimitedRetryTransport{}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d (4xx other than 429 is not retried)", resp.StatusCode, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,135 @@
+// Package manifest provides preprocessing utilities for raw Cloud Foundry application manifests,
+// applied before the bytes are decoded into the canonical Application model.
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// placeholderPattern matches a CF variable placeholder, e.g. `((name))`.
+var placeholderPattern = regexp.MustCompile(`\(\(([a-zA-Z0-9_.\-]+)\)\)`)
+
+// fullScalarPlaceholderPattern matches a scalar node whose entire content is a single placeholder,
+// e.g. `((instances))`, as opposed to one embedded in a larger string such as `prefix-((name))`.
+var fullScalarPlaceholderPattern = regexp.MustCompile(`^\(\(([a-zA-Z0-9_.\-]+)\)\)$`)
+
+// UnresolvedVariablesError is returned by ApplySubstitution when one or more `((name))` placeholders
+// in the manifest have no matching entry across the supplied vars maps.
+type UnresolvedVariablesError struct {
+	// Names lists every unresolved placeholder name once, in sorted order.
+	Names []string
+}
+
+func (e *UnresolvedVariablesError) Error() string {
+	return fmt.Sprintf("unresolved manifest variables: %s", strings.Join(e.Names, ", "))
+}
+
+// ApplySubstitution scans src, a YAML or JSON Cloud Foundry manifest, for `((identifier))` placeholders
+// and replaces them with values looked up in vars, in order, first match wins. vars is typically the
+// merged contents of one or more `--vars-file` YAML files plus `--var key=value` CLI flags.
+//
+// When a placeholder occupies an entire scalar node (e.g. `instances: ((n))`), the replacement preserves
+// the YAML type of the looked-up value, so an int or bool vars entry produces an int or bool node rather
+// than a string. Placeholders embedded within a larger scalar (e.g. `name: app-((suffix))`) are always
+// substituted as their string representation.
+//
+// Substitution is single-pass: a replacement value is never re-scanned for further placeholders, so
+// nesting cannot cause infinite loops. If any placeholder cannot be resolved, ApplySubstitution returns
+// an *UnresolvedVariablesError listing every unresolved name instead of a partially substituted manifest.
+func ApplySubstitution(src []byte, vars ...map[string]interface{}) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(src, &root); err != nil {
+		return nil, fmt.Errorf("parsing manifest for variable substitution: %w", err)
+	}
+
+	var missing []string
+	seen := map[string]bool{}
+	walkScalars(&root, func(n *yaml.Node) {
+		substituteScalar(n, vars, &missing, seen)
+	})
+
+	if len(missing) > 0 {
+		return nil, &UnresolvedVariablesError{Names: missing}
+	}
+
+	out, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, fmt.Errorf("rendering manifest after variable substitution: %w", err)
+	}
+	return out, nil
+}
+
+// walkScalars invokes fn for every scalar node in the tree rooted at n.
+func walkScalars(n *yaml.Node, fn func(*yaml.Node)) {
+	if n.Kind == yaml.ScalarNode {
+		fn(n)
+		return
+	}
+	for _, c := range n.Content {
+		walkScalars(c, fn)
+	}
+}
+
+func substituteScalar(n *yaml.Node, vars []map[string]interface{}, missing *[]string, seen map[string]bool) {
+	if full := fullScalarPlaceholderPattern.FindStringSubmatch(n.Value); full != nil {
+		name := full[1]
+		value, ok := lookup(vars, name)
+		if !ok {
+			recordMissing(name, missing, seen)
+			return
+		}
+		assignTyped(n, value)
+		return
+	}
+
+	if !placeholderPattern.MatchString(n.Value) {
+		return
+	}
+
+	n.Value = placeholderPattern.ReplaceAllStringFunc(n.Value, func(token string) string {
+		name := placeholderPattern.FindStringSubmatch(token)[1]
+		value, ok := lookup(vars, name)
+		if !ok {
+			recordMissing(name, missing, seen)
+			return token
+		}
+		return fmt.Sprint(value)
+	})
+}
+
+func recordMissing(name string, missing *[]string, seen map[string]bool) {
+	if seen[name] {
+		return
+	}
+	seen[name] = true
+	*missing = append(*missing, name)
+	sort.Strings(*missing)
+}
+
+func lookup(vars []map[string]interface{}, name string) (interface{}, bool) {
+	for _, m := range vars {
+		if v, ok := m[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// assignTyped rewrites scalar node n in place to hold value, preserving its YAML type (int, bool,
+// float, or string) rather than stringifying it.
+func assignTyped(n *yaml.Node, value interface{}) {
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		n.Value = fmt.Sprint(value)
+		n.Tag = "!!str"
+		return
+	}
+	n.Value = encoded.Value
+	n.Tag = encoded.Tag
+	n.Style = encoded.Style
+}
@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestApplySubstitution_TypedScalar(t *testing.T) {
+	src := []byte("instances: ((count))\nname: app\n")
+	out, err := ApplySubstitution(src, map[string]interface{}{"count": 3})
+	if err != nil {
+		t.Fatalf("ApplySubstitution: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("unmarshaling result: %v", err)
+	}
+	if v, ok := doc["instances"].(int); !ok || v != 3 {
+		t.Fatalf("instances = %#v, want int 3", doc["instances"])
+	}
+}
+
+func TestApplySubstitution_EmbeddedScalarAlwaysString(t *testing.T) {
+	src := []byte("name: app-((suffix))\n")
+	out, err := ApplySubstitution(src, map[string]interface{}{"suffix": 7})
+	if err != nil {
+		t.Fatalf("ApplySubstitution: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("unmarshaling result: %v", err)
+	}
+	if doc["name"] != "app-7" {
+		t.Fatalf("name = %#v, want \"app-7\"", doc["name"])
+	}
+}
+
+func TestApplySubstitution_FirstMatchWins(t *testing.T) {
+	src := []byte("name: ((app_name))\n")
+	out, err := ApplySubstitution(src,
+		map[string]interface{}{"app_name": "from-first"},
+		map[string]interface{}{"app_name": "from-second"},
+	)
+	if err != nil {
+		t.Fatalf("ApplySubstitution: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("unmarshaling result: %v", err)
+	}
+	if doc["name"] != "from-first" {
+		t.Fatalf("name = %#v, want \"from-first\"", doc["name"])
+	}
+}
+
+func TestApplySubstitution_NoNestedRescan(t *testing.T) {
+	src := []byte("name: ((a))\n")
+	out, err := ApplySubstitution(src, map[string]interface{}{"a": "((b))"})
+	if err != nil {
+		t.Fatalf("ApplySubstitution: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("unmarshaling result: %v", err)
+	}
+	if doc["name"] != "((b))" {
+		t.Fatalf("name = %#v, want literal \"((b))\" (no second pass)", doc["name"])
+	}
+}
+
+func TestApplySubstitution_UnresolvedVariablesError(t *testing.T) {
+	src := []byte("name: ((missing_one))\ninstances: ((missing_two))\n")
+	_, err := ApplySubstitution(src)
+
+	var unresolved *UnresolvedVariablesError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("err = %v, want *UnresolvedVariablesError", err)
+	}
+	if len(unresolved.Names) != 2 {
+		t.Fatalf("Names = %v, want 2 entries", unresolved.Names)
+	}
+	if unresolved.Names[0] != "missing_one" || unresolved.Names[1] != "missing_two" {
+		t.Fatalf("Names = %v, want [missing_one missing_two] in sorted order", unresolved.Names)
+	}
+}
+
+func TestApplySubstitution_UnresolvedVariablesDeduplicated(t *testing.T) {
+	src := []byte("a: ((x))\nb: ((x))\n")
+	_, err := ApplySubstitution(src)
+
+	var unresolved *UnresolvedVariablesError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("err = %v, want *UnresolvedVariablesError", err)
+	}
+	if len(unresolved.Names) != 1 {
+		t.Fatalf("Names = %v, want a single deduplicated entry", unresolved.Names)
+	}
+}
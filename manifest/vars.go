@@ -0,0 +1,34 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadVarsFile reads a `--vars-file` YAML document and returns its top-level keys as a vars map
+// suitable for ApplySubstitution.
+func LoadVarsFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading vars file %q: %w", path, err)
+	}
+	vars := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &vars); err != nil {
+		return nil, fmt.Errorf("parsing vars file %q: %w", path, err)
+	}
+	return vars, nil
+}
+
+// ParseVarFlag parses a single `--var key=value` CLI flag into a name/value pair. The value is always
+// a string, matching CF CLI behaviour where `--var` does not support typed values; use `--vars-file` for
+// non-string substitutions.
+func ParseVarFlag(flag string) (string, interface{}, error) {
+	name, value, ok := strings.Cut(flag, "=")
+	if !ok {
+		return "", nil, fmt.Errorf("invalid --var flag %q, expected key=value", flag)
+	}
+	return name, value, nil
+}
@@ -0,0 +1,213 @@
+// Package server exposes discovered Cloud Foundry Application values, canonicalized and cached,
+// through a small read-only REST API. It is the implementation behind the `cf-discovery serve`
+// subcommand: heavy consumers (dashboards, migration tooling) hit this instead of polling the
+// Cloud Controller API directly.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jordigilh/cf-discovery-canonical-form/canonical"
+	"github.com/jordigilh/cf-discovery-canonical-form/server/store"
+)
+
+// DefaultRefreshInterval is the TTL applied to cached Application entries, and the default interval
+// between Cloud Controller polls, when --refresh-interval is not set.
+const DefaultRefreshInterval = 5 * time.Minute
+
+// Discoverer canonicalizes every application in every org/space of a CF foundation. It is
+// satisfied by discovery/cc.Client.
+type Discoverer interface {
+	DiscoverAll(ctx context.Context) ([]canonical.Application, error)
+}
+
+// Config configures a Server.
+type Config struct {
+	// Discoverer polls the live Cloud Controller API on every refresh.
+	Discoverer Discoverer
+	// Store caches canonicalized Application JSON between refreshes. Defaults to an in-memory
+	// Store when nil.
+	Store store.Store
+	// RefreshInterval is both the poll interval and the cache TTL. Defaults to
+	// DefaultRefreshInterval when zero.
+	RefreshInterval time.Duration
+}
+
+// Server periodically polls a Discoverer and serves the canonicalized results, cached in Store,
+// over HTTP.
+type Server struct {
+	discoverer Discoverer
+	store      store.Store
+	interval   time.Duration
+
+	lastRefresh    atomic.Value // time.Time
+	lastRefreshErr atomic.Value // error, boxed as errorBox
+
+	generation atomic.Uint64
+}
+
+type errorBox struct{ err error }
+
+// New constructs a Server from cfg. Call Start to begin polling and Handler to obtain the HTTP
+// handler.
+func New(cfg Config) *Server {
+	s := cfg.Store
+	if s == nil {
+		s = store.NewMemory()
+	}
+	interval := cfg.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	srv := &Server{discoverer: cfg.Discoverer, store: s, interval: interval}
+	srv.lastRefreshErr.Store(errorBox{})
+	return srv
+}
+
+// Start blocks, refreshing the cache every RefreshInterval until ctx is cancelled. Run it in its
+// own goroutine.
+func (s *Server) Start(ctx context.Context) {
+	s.refresh(ctx)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+func (s *Server) refresh(ctx context.Context) {
+	apps, err := s.discoverer.DiscoverAll(ctx)
+	if err != nil {
+		log.Printf("cf-discovery: refresh failed: %v", err)
+		s.lastRefreshErr.Store(errorBox{err: err})
+		return
+	}
+
+	groups := map[string][]canonical.Application{}
+	groups[appsKey("", "", "")] = apps
+	for _, a := range apps {
+		groups[appsKey(a.Metadata.Org, "", "")] = append(groups[appsKey(a.Metadata.Org, "", "")], a)
+		groups[appsKey(a.Metadata.Org, a.Metadata.Space, "")] = append(groups[appsKey(a.Metadata.Org, a.Metadata.Space, "")], a)
+		groups[appsKey(a.Metadata.Org, a.Metadata.Space, a.Metadata.Name)] = append(groups[appsKey(a.Metadata.Org, a.Metadata.Space, a.Metadata.Name)], a)
+	}
+
+	generation := s.generation.Load() + 1
+	for key, matched := range groups {
+		payload, err := json.Marshal(matched)
+		if err != nil {
+			s.lastRefreshErr.Store(errorBox{err: fmt.Errorf("encoding %q: %w", key, err)})
+			return
+		}
+		if err := s.store.Set(key, payload, generation, s.interval); err != nil {
+			s.lastRefreshErr.Store(errorBox{err: fmt.Errorf("caching %q: %w", key, err)})
+			return
+		}
+	}
+	s.generation.Store(generation)
+
+	s.lastRefresh.Store(time.Now())
+	s.lastRefreshErr.Store(errorBox{})
+}
+
+// Handler returns the http.Handler exposing /v2/apps, /v2/apps/{org}, /v2/apps/{org}/{space},
+// /v2/apps/{org}/{space}/{name} and /healthz.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/apps", s.handleApps)
+	mux.HandleFunc("/v2/apps/", s.handleApps)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	return mux
+}
+
+func (s *Server) handleApps(w http.ResponseWriter, r *http.Request) {
+	org, space, name := parseAppsPath(r.URL.Path)
+	key := appsKey(org, space, name)
+
+	entry, ok := s.store.Get(key)
+
+	etag := strconv.FormatUint(entry.Generation, 10)
+	if match := r.Header.Get("If-None-Match"); match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	payload := entry.Value
+	if !ok {
+		payload = []byte("[]")
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(payload)
+}
+
+// appsKey builds the cache key for a given org/space/name combination; an empty string selects
+// everything at that level, matching the /v2/apps[/{org}[/{space}[/{name}]]] route hierarchy.
+func appsKey(org, space, name string) string {
+	return fmt.Sprintf("apps/%s/%s/%s", org, space, name)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	type health struct {
+		LastRefresh time.Time `json:"lastRefresh,omitempty"`
+		Error       string    `json:"error,omitempty"`
+	}
+	h := health{}
+	if t, ok := s.lastRefresh.Load().(time.Time); ok {
+		h.LastRefresh = t
+	}
+	if box, ok := s.lastRefreshErr.Load().(errorBox); ok && box.err != nil {
+		h.Error = box.err.Error()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(h)
+}
+
+// parseAppsPath splits the trailing /org/space/name segments off a /v2/apps path.
+func parseAppsPath(path string) (org, space, name string) {
+	const prefix = "/v2/apps/"
+	if len(path) <= len(prefix) {
+		return "", "", ""
+	}
+	rest := path[len(prefix):]
+	segments := splitNonEmpty(rest, '/')
+	switch len(segments) {
+	case 1:
+		return segments[0], "", ""
+	case 2:
+		return segments[0], segments[1], ""
+	case 3:
+		return segments[0], segments[1], segments[2]
+	default:
+		return "", "", ""
+	}
+}
+
+func splitNonEmpty(s string, sep byte) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == sep {
+			if i > start {
+				out = append(out, s[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		out = append(out, s[start:])
+	}
+	return out
+}
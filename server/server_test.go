@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jordigilh/cf-discovery-canonical-form/canonical"
+)
+
+type fakeDiscoverer struct {
+	apps []canonical.Application
+	err  error
+}
+
+func (f *fakeDiscoverer) DiscoverAll(ctx context.Context) ([]canonical.Application, error) {
+	return f.apps, f.err
+}
+
+func newTestServer(t *testing.T, apps []canonical.Application) *Server {
+	t.Helper()
+	srv := New(Config{Discoverer: &fakeDiscoverer{apps: apps}})
+	srv.refresh(context.Background())
+	return srv
+}
+
+func TestHandleApps_RoutesByOrgSpaceName(t *testing.T) {
+	apps := []canonical.Application{
+		{Metadata: canonical.Metadata{Name: "app-1", Org: "org-a", Space: "space-a"}},
+		{Metadata: canonical.Metadata{Name: "app-2", Org: "org-b", Space: "space-b"}},
+	}
+	srv := newTestServer(t, apps)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantCount int
+	}{
+		{"all apps", "/v2/apps", 2},
+		{"by org", "/v2/apps/org-a", 1},
+		{"by org and space", "/v2/apps/org-a/space-a", 1},
+		{"by org, space and name", "/v2/apps/org-a/space-a/app-1", 1},
+		{"unknown org", "/v2/apps/org-z", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if tt.wantCount == 0 {
+				if rec.Body.String() != "[]" {
+					t.Fatalf("body = %q, want empty array", rec.Body.String())
+				}
+				return
+			}
+			var got []json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decoding body %q: %v", rec.Body.String(), err)
+			}
+			if len(got) != tt.wantCount {
+				t.Fatalf("got %d apps, want %d", len(got), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestHandleApps_ETag(t *testing.T) {
+	srv := newTestServer(t, []canonical.Application{
+		{Metadata: canonical.Metadata{Name: "app-1"}},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/apps", nil)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("response had no ETag header")
+	}
+
+	req2 := httptest.NewRequest(http.MethodGet, "/v2/apps", nil)
+	req2.Header.Set("If-None-Match", etag)
+	rec2 := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec2, req2)
+
+	if rec2.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want %d for matching If-None-Match", rec2.Code, http.StatusNotModified)
+	}
+}
+
+func TestHandleApps_ETagChangesAcrossRefresh(t *testing.T) {
+	srv := newTestServer(t, []canonical.Application{{Metadata: canonical.Metadata{Name: "app-1"}}})
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/apps", nil)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	firstETag := rec.Header().Get("ETag")
+
+	srv.refresh(context.Background())
+
+	req2 := httptest.NewRequest(http.MethodGet, "/v2/apps", nil)
+	req2.Header.Set("If-None-Match", firstETag)
+	rec2 := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec2, req2)
+
+	if rec2.Code == http.StatusNotModified {
+		t.Fatalf("status = %d, want a fresh response after a new refresh bumped the generation", rec2.Code)
+	}
+}
+
+func TestHandleHealthz_OK(t *testing.T) {
+	srv := newTestServer(t, []canonical.Application{{Metadata: canonical.Metadata{Name: "app-1"}}})
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestHandleHealthz_ReportsRefreshError(t *testing.T) {
+	srv := New(Config{Discoverer: &fakeDiscoverer{err: context.DeadlineExceeded}})
+	srv.refresh(context.Background())
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
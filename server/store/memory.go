@@ -0,0 +1,45 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryEntry is a store Entry plus the absolute time it expires at.
+type memoryEntry struct {
+	Entry
+	expiresAt time.Time
+}
+
+// Memory is the default in-process Store. It is safe for concurrent use.
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+}
+
+// NewMemory constructs an empty in-memory Store.
+func NewMemory() *Memory {
+	return &Memory{entries: map[string]memoryEntry{}}
+}
+
+// Get implements Store.
+func (m *Memory) Get(key string) (Entry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return Entry{}, false
+	}
+	return e.Entry, true
+}
+
+// Set implements Store.
+func (m *Memory) Set(key string, value []byte, generation uint64, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryEntry{
+		Entry:     Entry{Value: value, Generation: generation},
+		expiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
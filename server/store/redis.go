@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is an optional Store backed by a shared Redis instance, for deployments running more than
+// one `cf-discovery serve` replica that must agree on cached generations.
+type Redis struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedis constructs a Redis-backed Store. keyPrefix namespaces all keys written by this Store,
+// so a single Redis instance can be shared across environments.
+func NewRedis(client *redis.Client, keyPrefix string) *Redis {
+	return &Redis{client: client, prefix: keyPrefix}
+}
+
+// NewRedisAddr is a convenience constructor for the common case of a single Redis instance
+// addressed by host:port, namespacing keys under "cf-discovery:".
+func NewRedisAddr(addr string) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("connecting to redis at %q: %w", addr, err)
+	}
+	return NewRedis(client, "cf-discovery:"), nil
+}
+
+// Get implements Store.
+func (r *Redis) Get(key string) (Entry, bool) {
+	ctx := context.Background()
+	data, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if err != nil {
+		return Entry{}, false
+	}
+	if len(data) < 8 {
+		return Entry{}, false
+	}
+	return Entry{
+		Generation: binary.BigEndian.Uint64(data[:8]),
+		Value:      data[8:],
+	}, true
+}
+
+// Set implements Store.
+func (r *Redis) Set(key string, value []byte, generation uint64, ttl time.Duration) error {
+	ctx := context.Background()
+	buf := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(buf[:8], generation)
+	copy(buf[8:], value)
+	if err := r.client.Set(ctx, r.prefix+key, buf, ttl).Err(); err != nil {
+		return fmt.Errorf("writing %q to redis: %w", key, err)
+	}
+	return nil
+}
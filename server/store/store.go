@@ -0,0 +1,25 @@
+// Package store provides pluggable caches for discovered Application JSON, keyed by org/space/name,
+// with a generation counter used to drive HTTP ETag validation.
+package store
+
+import "time"
+
+// Entry is a single cached value together with the generation it was written at, so callers can
+// answer If-None-Match checks without re-fetching the value itself.
+type Entry struct {
+	// Value holds the cached canonical JSON payload.
+	Value []byte
+	// Generation identifies which refresh cycle produced Value. It increments on every refresh
+	// and is surfaced to clients as the ETag.
+	Generation uint64
+}
+
+// Store is a pluggable cache for discovered Application JSON. The in-memory implementation is the
+// default; a Redis-backed implementation is available for multi-replica deployments of
+// `cf-discovery serve` that need a shared cache.
+type Store interface {
+	// Get returns the cached entry for key, or ok=false if absent or expired.
+	Get(key string) (entry Entry, ok bool)
+	// Set stores value under key at the given generation, expiring after ttl.
+	Set(key string, value []byte, generation uint64, ttl time.Duration) error
+}
@@ -0,0 +1,56 @@
+// Package units converts Cloud Foundry's binary (1024-based) memory, disk and log-rate suffixes
+// into Kubernetes-compatible resource.Quantity strings.
+package units
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// quantityPattern splits a CF size string into its numeric and suffix parts, e.g. "512M" -> ("512", "M").
+var quantityPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)$`)
+
+// binarySuffixes maps CF's binary suffixes (and their already-K8s-binary equivalents) to the
+// Kubernetes binary suffix that k8s.io/apimachinery/pkg/api/resource.ParseQuantity expects.
+var binarySuffixes = map[string]string{
+	"":   "",
+	"B":  "",
+	"K":  "Ki",
+	"KB": "Ki",
+	"KI": "Ki",
+	"M":  "Mi",
+	"MB": "Mi",
+	"MI": "Mi",
+	"G":  "Gi",
+	"GB": "Gi",
+	"GI": "Gi",
+	"T":  "Ti",
+	"TB": "Ti",
+	"TI": "Ti",
+}
+
+// CFToSI rewrites a Cloud Foundry size string, whose bare K/M/G/T suffixes are 1024-based, into the
+// equivalent Kubernetes resource.Quantity string. Suffixes already expressed in Kubernetes binary form
+// (Ki, Mi, Gi, Ti) are passed through unchanged, and bare bytes ("512", "512B") are passed through with
+// no suffix. CFToSI returns an error if orig does not parse as a number followed by a known suffix.
+func CFToSI(orig string) (string, error) {
+	trimmed := strings.TrimSpace(orig)
+	match := quantityPattern.FindStringSubmatch(trimmed)
+	if match == nil {
+		return "", fmt.Errorf("parsing CF quantity %q: not a number followed by a unit suffix", orig)
+	}
+
+	number, suffix := match[1], strings.ToUpper(match[2])
+	siSuffix, ok := binarySuffixes[suffix]
+	if !ok {
+		return "", fmt.Errorf("parsing CF quantity %q: unknown suffix %q", orig, match[2])
+	}
+
+	if _, err := strconv.ParseFloat(number, 64); err != nil {
+		return "", fmt.Errorf("parsing CF quantity %q: %w", orig, err)
+	}
+
+	return number + siSuffix, nil
+}
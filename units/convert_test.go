@@ -0,0 +1,54 @@
+package units
+
+import "testing"
+
+func TestCFToSI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"bare K", "512K", "512Ki"},
+		{"KB alias", "512KB", "512Ki"},
+		{"already K8s binary", "512Ki", "512Ki"},
+		{"M suffix", "1024M", "1024Mi"},
+		{"G suffix", "2G", "2Gi"},
+		{"T suffix", "1T", "1Ti"},
+		{"bare bytes no suffix", "512", "512"},
+		{"bare bytes with B suffix", "512B", "512"},
+		{"lowercase suffix", "512m", "512Mi"},
+		{"fractional number", "1.5G", "1.5Gi"},
+		{"surrounding whitespace", "  512M  ", "512Mi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CFToSI(tt.in)
+			if err != nil {
+				t.Fatalf("CFToSI(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Fatalf("CFToSI(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCFToSI_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"unknown suffix", "512X"},
+		{"not a number", "abc"},
+		{"empty string", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CFToSI(tt.in); err == nil {
+				t.Fatalf("CFToSI(%q) succeeded, want error", tt.in)
+			}
+		})
+	}
+}